@@ -0,0 +1,121 @@
+// Command embedmeup embeds JSON records with OpenAI and stores them in a
+// vector store (Pinecone, or a local bbolt file with -store local) for later
+// retrieval. Usage:
+//
+//	embedmeup [global flags] <upsert|retrieve|delete|stats> [subcommand flags]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/tbiehn/embedmeup/internal/app"
+	"github.com/tbiehn/embedmeup/internal/subcmd"
+	deletecmd "github.com/tbiehn/embedmeup/internal/subcmd/delete"
+	"github.com/tbiehn/embedmeup/internal/subcmd/retrieve"
+	"github.com/tbiehn/embedmeup/internal/subcmd/stats"
+	"github.com/tbiehn/embedmeup/internal/subcmd/upsert"
+)
+
+func commands() []subcmd.Command {
+	return []subcmd.Command{
+		upsert.New(),
+		retrieve.New(),
+		deletecmd.New(),
+		stats.New(),
+	}
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	var cfg app.Config
+
+	global := flag.NewFlagSet("embedmeup", flag.ExitOnError)
+	global.StringVar(&cfg.LogLevel, "l", "info", "l[og] level (options: debug, info, warn, error, fatal, panic)")
+	global.BoolVar(&cfg.DisableBar, "b", false, "b[ar] Disable the progress bar. Set -b all by itself to disable it.")
+	global.IntVar(&cfg.Concurrency, "p", 10, "p[arallel] How many parallel calls to make to OpenAI.")
+	global.StringVar(&cfg.EmbeddingsDirectory, "edir", "~/.embedmeup/embeddings/", "Where to store the raw embedding content.")
+	global.StringVar(&cfg.IndexName, "index", "", "Pinecone index name")
+	global.StringVar(&cfg.AccountRegion, "region", "", "Pinecone account region")
+	global.StringVar(&cfg.ProjectName, "project", "", "Pinecone project name")
+	global.StringVar(&cfg.Namespace, "namespace", "", "Index namespace")
+	global.StringVar(&cfg.AzureEndpoint, "ae", "", "a[zure]e[ndpoint] Set if using Azure. Your OpenAI HTTP Endpoint. Set environment variable 'AZUREAI_API_KEY' to your API key.")
+
+	var store string
+	global.StringVar(&store, "store", string(app.StorePinecone), "Vector store backend: pinecone or local.")
+
+	global.StringVar(&cfg.MetricsAddr, "metrics-addr", "", "Address to serve Prometheus metrics on, e.g. ':9090'. Disabled if empty.")
+	global.BoolVar(&cfg.MetricsBasicAuth, "metrics-basic-auth", false, "Require HTTP basic auth on the metrics endpoint, from METRICS_BASIC_AUTH_USER/METRICS_BASIC_AUTH_PASS.")
+
+	var timeout, requestTimeout time.Duration
+	global.DurationVar(&timeout, "timeout", 0, "Overall deadline for the whole run, e.g. '30m'. No deadline if 0.")
+	global.DurationVar(&requestTimeout, "request-timeout", 60*time.Second, "Per-request deadline for each OpenAI/vector store call.")
+
+	cmds := commands()
+
+	global.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: embedmeup [global flags] <command> [command flags]\n\nCommands:\n")
+		for _, c := range cmds {
+			fmt.Fprintf(os.Stderr, "  %s\n", c.Name())
+		}
+		fmt.Fprintf(os.Stderr, "\nGlobal flags:\n")
+		global.PrintDefaults()
+	}
+
+	if err := global.Parse(args); err != nil {
+		return err
+	}
+
+	rest := global.Args()
+	if len(rest) == 0 {
+		global.Usage()
+		return fmt.Errorf("no command given")
+	}
+
+	name, cmdArgs := rest[0], rest[1:]
+
+	var cmd subcmd.Command
+	for _, c := range cmds {
+		if c.Name() == name {
+			cmd = c
+			break
+		}
+	}
+	if cmd == nil {
+		return fmt.Errorf("unknown command: %s", name)
+	}
+
+	if err := cmd.FlagSet().Parse(cmdArgs); err != nil {
+		return err
+	}
+
+	cfg.Store = app.StoreBackend(store)
+	cfg.RequestTimeout = requestTimeout
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	deps, err := app.New(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	return cmd.Run(ctx, deps)
+}
@@ -0,0 +1,262 @@
+// Package app owns the setup shared by every embedmeup subcommand: logger,
+// OpenAI/Azure client, vector store (Pinecone or local), content store, and
+// the on-disk embeddings directory.
+// Subcommands receive a *Deps instead of touching os.Args or package globals,
+// which keeps upsertEmbeddings/retrieveEmbeddings-style logic unit-testable.
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	pinecone "github.com/nekomeowww/go-pinecone"
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sirupsen/logrus"
+	"github.com/vbauerster/mpb/v8"
+
+	gptparallel "github.com/tbiehn/gptparallel"
+
+	"github.com/tbiehn/embedmeup/internal/contentstore"
+	contentlocalfs "github.com/tbiehn/embedmeup/internal/contentstore/localfs"
+	"github.com/tbiehn/embedmeup/internal/metrics"
+	"github.com/tbiehn/embedmeup/internal/vectorstore"
+	vectorlocalfs "github.com/tbiehn/embedmeup/internal/vectorstore/localfs"
+	vectorpinecone "github.com/tbiehn/embedmeup/internal/vectorstore/pinecone"
+)
+
+// StoreBackend names one of the vectorstore.Store implementations New can
+// build.
+type StoreBackend string
+
+const (
+	// StorePinecone talks to a hosted Pinecone index.
+	StorePinecone StoreBackend = "pinecone"
+	// StoreLocal keeps vectors in a bbolt file under EmbeddingsDirectory, for
+	// offline use and tests.
+	StoreLocal StoreBackend = "local"
+)
+
+// Config holds the flags that are shared across every subcommand. Subcommand
+// packages define their own flag-specific structs for anything narrower.
+type Config struct {
+	LogLevel            string
+	DisableBar          bool
+	Concurrency         int
+	EmbeddingsDirectory string
+	Store               StoreBackend
+
+	IndexName     string
+	AccountRegion string
+	ProjectName   string
+	Namespace     string
+
+	AzureEndpoint  string
+	AzureModelName string
+
+	// MetricsAddr, if non-empty, starts a Prometheus /metrics server on this
+	// address (e.g. ":9090").
+	MetricsAddr string
+	// MetricsBasicAuth gates the metrics endpoint behind HTTP basic auth,
+	// read from the METRICS_BASIC_AUTH_USER/METRICS_BASIC_AUTH_PASS
+	// environment variables, for deployments that expose it publicly.
+	MetricsBasicAuth bool
+
+	// RequestTimeout bounds each individual OpenAI/vector store call. Zero
+	// means no per-request deadline.
+	RequestTimeout time.Duration
+}
+
+// Deps is the set of shared collaborators injected into every subcommand's
+// Run method.
+type Deps struct {
+	Log                 *logrus.Logger
+	AIClient            *openai.Client
+	VectorStore         vectorstore.Store
+	ContentStore        contentstore.Store
+	EmbeddingsDirectory string
+	Namespace           string
+	RequestTimeout      time.Duration
+
+	GPT          *gptparallel.GPTParallel
+	RequestsChan chan gptparallel.VectorRequestWithCallback
+
+	Metrics       *metrics.Metrics
+	MetricsServer *http.Server
+}
+
+// New wires up the shared dependencies from cfg: it configures logging,
+// resolves the embeddings directory, and connects to OpenAI/Azure and the
+// configured vector store backend. The returned Deps is ready to hand to a
+// subcommand's Run method.
+func New(ctx context.Context, cfg Config) (*Deps, error) {
+	log := newLogger(cfg.LogLevel)
+
+	aiclient, err := newOpenAIClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	canonDir, err := filepath.Abs(expandHomeDir(cfg.EmbeddingsDirectory))
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Storing embedding chunks in %s", canonDir)
+	if _, err := os.Stat(canonDir); os.IsNotExist(err) {
+		if err := os.MkdirAll(canonDir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	store, err := newVectorStore(cfg, canonDir)
+	if err != nil {
+		return nil, err
+	}
+
+	backoffSettings := backoff.NewExponentialBackOff()
+
+	var bar *mpb.Progress
+	if !cfg.DisableBar {
+		bar = mpb.New(mpb.WithOutput(log.Out))
+	}
+
+	requestsChan := make(chan gptparallel.VectorRequestWithCallback, cfg.Concurrency*1000)
+	g := gptparallel.NewGPTParallel(ctx, aiclient, bar, backoffSettings, log)
+	gptResultsChan := g.RunEmbeddingsChan(requestsChan, cfg.Concurrency)
+
+	// Drain response channel; callers retrieve results via their own
+	// per-request callbacks, not this channel.
+	go func() {
+		for range gptResultsChan {
+		}
+	}()
+
+	m := metrics.New()
+	var metricsServer *http.Server
+	if cfg.MetricsAddr != "" {
+		auth, err := metricsBasicAuth(cfg)
+		if err != nil {
+			return nil, err
+		}
+		log.Infof("Serving Prometheus metrics on %s/metrics", cfg.MetricsAddr)
+		metricsServer = m.Serve(cfg.MetricsAddr, auth)
+	}
+
+	return &Deps{
+		Log:                 log,
+		AIClient:            aiclient,
+		VectorStore:         store,
+		ContentStore:        contentlocalfs.New(canonDir),
+		EmbeddingsDirectory: canonDir,
+		Namespace:           cfg.Namespace,
+		RequestTimeout:      cfg.RequestTimeout,
+		GPT:                 g,
+		RequestsChan:        requestsChan,
+		Metrics:             m,
+		MetricsServer:       metricsServer,
+	}, nil
+}
+
+// RequestContext returns ctx bounded by RequestTimeout, if one is configured,
+// along with its cancel function. Callers should always defer the returned
+// cancel, even when RequestTimeout is zero. Use it to scope each individual
+// OpenAI/vector store call so a single slow request can't hang the run past
+// its own timeout.
+func (d *Deps) RequestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.RequestTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d.RequestTimeout)
+}
+
+func metricsBasicAuth(cfg Config) (*metrics.BasicAuth, error) {
+	if !cfg.MetricsBasicAuth {
+		return nil, nil
+	}
+	user := os.Getenv("METRICS_BASIC_AUTH_USER")
+	pass := os.Getenv("METRICS_BASIC_AUTH_PASS")
+	if user == "" || pass == "" {
+		return nil, fmt.Errorf("METRICS_BASIC_AUTH_USER and METRICS_BASIC_AUTH_PASS must both be set when -metrics-basic-auth is used")
+	}
+	return &metrics.BasicAuth{Username: user, Password: pass}, nil
+}
+
+func newVectorStore(cfg Config, embeddingsDirectory string) (vectorstore.Store, error) {
+	switch cfg.Store {
+	case "", StorePinecone:
+		pineconeAPIKey := os.Getenv("PINECONE_API_KEY")
+		if pineconeAPIKey == "" {
+			return nil, errMissingEnv("PINECONE_API_KEY")
+		}
+		client, err := pinecone.NewIndexClient(
+			pinecone.WithIndexName(cfg.IndexName),
+			pinecone.WithEnvironment(cfg.AccountRegion),
+			pinecone.WithProjectName(cfg.ProjectName),
+			pinecone.WithAPIKey(pineconeAPIKey),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return vectorpinecone.New(client), nil
+	case StoreLocal:
+		store, err := vectorlocalfs.Open(filepath.Join(embeddingsDirectory, "vectors.db"))
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown store backend: %s", cfg.Store)
+	}
+}
+
+func newLogger(logLevel string) *logrus.Logger {
+	log := logrus.New()
+	log.Out = os.Stderr
+
+	level, err := logrus.ParseLevel(logLevel)
+	if err != nil {
+		log.Fatalf("Invalid log level: %v", err)
+	}
+	log.SetLevel(level)
+	log.SetFormatter(&logrus.TextFormatter{})
+	return log
+}
+
+func newOpenAIClient(cfg Config) (*openai.Client, error) {
+	if cfg.AzureEndpoint != "" {
+		azureAPIKey := os.Getenv("AZUREAI_API_KEY")
+		if azureAPIKey == "" {
+			return nil, errMissingEnv("AZUREAI_API_KEY")
+		}
+		config := openai.DefaultAzureConfig(azureAPIKey, cfg.AzureEndpoint)
+		return openai.NewClientWithConfig(config), nil
+	}
+
+	openAIKey := os.Getenv("OPENAI_API_KEY")
+	if openAIKey == "" {
+		return nil, errMissingEnv("OPENAI_API_KEY")
+	}
+	return openai.NewClient(openAIKey), nil
+}
+
+func expandHomeDir(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return path
+		}
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
+
+type errMissingEnv string
+
+func (e errMissingEnv) Error() string {
+	return string(e) + " environment variable not set"
+}
@@ -0,0 +1,74 @@
+package app_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/tbiehn/embedmeup/internal/contentstore"
+	contentlocalfs "github.com/tbiehn/embedmeup/internal/contentstore/localfs"
+	"github.com/tbiehn/embedmeup/internal/vectorstore"
+	vectorlocalfs "github.com/tbiehn/embedmeup/internal/vectorstore/localfs"
+)
+
+// TestUpsertRetrieveRoundTrip exercises the StoreLocal backend's upsert and
+// retrieve paths end to end, without OpenAI or Pinecone: it stores a record's
+// content and vector the way the upsert subcommand does, then queries and
+// fetches it back the way the retrieve subcommand does. This is the
+// offline-testability the StoreLocal backend and contentstore.Store interface
+// exist to provide.
+func TestUpsertRetrieveRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	contentStore := contentlocalfs.New(dir)
+	vectorStore, err := vectorlocalfs.Open(filepath.Join(dir, "vectors.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer vectorStore.Close()
+
+	records := []struct {
+		id     string
+		data   map[string]any
+		vector []float32
+	}{
+		{id: "doc-1", data: map[string]any{"search": "cats are great"}, vector: []float32{1, 0, 0}},
+		{id: "doc-2", data: map[string]any{"search": "dogs are great"}, vector: []float32{0, 1, 0}},
+	}
+
+	for _, r := range records {
+		jsonData, err := json.Marshal(r.data)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if err := contentStore.Put(ctx, r.id, jsonData); err != nil {
+			t.Fatalf("Put(%s): %v", r.id, err)
+		}
+		if err := vectorStore.Upsert(ctx, []vectorstore.Record{{ID: r.id, Vector: r.vector}}); err != nil {
+			t.Fatalf("Upsert(%s): %v", r.id, err)
+		}
+	}
+
+	matches, err := vectorStore.Query(ctx, []float32{1, 0, 0}, 1, nil)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(matches) != 1 || matches[0].ID != "doc-1" {
+		t.Fatalf("Query returned %+v, want a single match for doc-1", matches)
+	}
+
+	assertContent(t, ctx, contentStore, "doc-1", `{"search":"cats are great"}`)
+}
+
+func assertContent(t *testing.T, ctx context.Context, store contentstore.Store, id, want string) {
+	t.Helper()
+	data, err := store.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", id, err)
+	}
+	if string(data) != want {
+		t.Fatalf("Get(%s) = %s, want %s", id, data, want)
+	}
+}
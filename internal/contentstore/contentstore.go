@@ -0,0 +1,15 @@
+// Package contentstore defines where embedmeup keeps the original JSON
+// payload behind each embedded vector, separately from the vector itself.
+// Today the only implementation is localfs; S3/GCS-backed stores can satisfy
+// the same interface later without touching upsert/retrieve.
+package contentstore
+
+import "context"
+
+// Store persists and retrieves the original JSON content for a given vector
+// ID.
+type Store interface {
+	Put(ctx context.Context, id string, data []byte) error
+	Get(ctx context.Context, id string) ([]byte, error)
+	Exists(ctx context.Context, id string) (bool, error)
+}
@@ -0,0 +1,48 @@
+// Package localfs is the contentstore.Store implementation that writes each
+// record's original JSON to a file named after its ID, under a configured
+// directory. This is the behavior embedmeup has always had; it is now just
+// behind an interface.
+package localfs
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Store writes content to files named <dir>/<id>.
+type Store struct {
+	dir string
+}
+
+// New returns a Store rooted at dir. dir must already exist.
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) Put(ctx context.Context, id string, data []byte) error {
+	if err := ioutil.WriteFile(filepath.Join(s.dir, id), data, 0644); err != nil {
+		return fmt.Errorf("error writing content for %s: %w", id, err)
+	}
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, id string) ([]byte, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, id))
+	if err != nil {
+		return nil, fmt.Errorf("error reading content for %s: %w", id, err)
+	}
+	return data, nil
+}
+
+func (s *Store) Exists(ctx context.Context, id string) (bool, error) {
+	if _, err := os.Stat(filepath.Join(s.dir, id)); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,129 @@
+// Package embed holds the OpenAI embedding helpers shared by the upsert and
+// retrieve subcommands: computing a single embedding via gptparallel, and
+// recursively bisecting text that is too long for the model's token limit.
+package embed
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkoukk/tiktoken-go"
+	openai "github.com/sashabaranov/go-openai"
+	gptparallel "github.com/tbiehn/gptparallel"
+
+	"github.com/tbiehn/embedmeup/internal/metrics"
+)
+
+var encoding, _ = tiktoken.EncodingForModel("gpt-3.5-turbo")
+
+const model = "text-embedding-ada-002"
+
+// Compute requests an embedding for text over embedClient and blocks until
+// the result is available or ctx is done, whichever comes first. m may be
+// nil, in which case no metrics are recorded.
+func Compute(ctx context.Context, text string, embedClient chan gptparallel.VectorRequestWithCallback, m *metrics.Metrics) ([]float32, error) {
+	// Embedding computation fails on empty strings.
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("empty string requested for embedding")
+	}
+
+	start := time.Now()
+
+	done := make(chan gptparallel.VectorRequestResult, 1)
+	select {
+	case embedClient <- gptparallel.VectorRequestWithCallback{
+		Request: openai.EmbeddingRequest{
+			Input: []string{text},
+			Model: openai.AdaEmbeddingV2,
+		},
+		Callback: func(inresult gptparallel.VectorRequestResult) {
+			done <- inresult
+		},
+		Identifier: text,
+	}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var result []float32
+	select {
+	case inresult := <-done:
+		result = inresult.Vector
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if m != nil {
+		m.OpenAIRequestDuration.WithLabelValues(model).Observe(time.Since(start).Seconds())
+	}
+
+	if len(result) == 0 {
+		if m != nil {
+			m.OpenAIRequestsTotal.WithLabelValues(model, "error").Inc()
+		}
+		return nil, fmt.Errorf("problem processing vector for input [%s]", text)
+	}
+
+	if m != nil {
+		m.OpenAIRequestsTotal.WithLabelValues(model, "ok").Inc()
+		m.OpenAITokensTotal.WithLabelValues("input").Add(float64(Encode(text)))
+	}
+
+	return result, nil
+}
+
+// BisectSplitTokens splits text into newline-bounded chunks of at most
+// targetTokenCount tokens, recursively bisecting any chunk (by words, then by
+// characters) that still exceeds the limit on its own.
+func BisectSplitTokens(text string, targetTokenCount int) []string {
+	lines := strings.Split(text, "\n")
+	var chunks []string
+	currentChunk := ""
+	for _, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		if trimmedLine == "" {
+			continue
+		}
+		newLine := "\n" + trimmedLine
+		if currentChunk == "" {
+			newLine = trimmedLine
+		}
+		newTokenCount := len(encoding.Encode(currentChunk+newLine, nil, nil))
+		if newTokenCount <= targetTokenCount {
+			currentChunk += newLine
+		} else {
+			chunks = append(chunks, currentChunk)
+			currentChunk = trimmedLine
+		}
+	}
+	if currentChunk != "" {
+		chunks = append(chunks, currentChunk)
+	}
+
+	var bisectedChunks []string
+	for _, chunk := range chunks {
+		if len(encoding.Encode(chunk, nil, nil)) > targetTokenCount {
+			words := strings.Split(chunk, " ")
+			if len(words) > 1 {
+				mid := len(words) / 2
+				bisectedChunks = append(bisectedChunks, BisectSplitTokens(strings.Join(words[:mid], " "), targetTokenCount)...)
+				bisectedChunks = append(bisectedChunks, BisectSplitTokens(strings.Join(words[mid:], " "), targetTokenCount)...)
+			} else {
+				mid := len(chunk) / 2
+				bisectedChunks = append(bisectedChunks, BisectSplitTokens(chunk[:mid], targetTokenCount)...)
+				bisectedChunks = append(bisectedChunks, BisectSplitTokens(chunk[mid:], targetTokenCount)...)
+			}
+		} else {
+			bisectedChunks = append(bisectedChunks, chunk)
+		}
+	}
+	return bisectedChunks
+}
+
+// Encode returns the token count for text under the model encoding used to
+// decide when a chunk needs bisecting.
+func Encode(text string) int {
+	return len(encoding.Encode(text, nil, nil))
+}
@@ -0,0 +1,123 @@
+// Package metrics instruments the embedding pipeline with Prometheus
+// collectors, so long-running batch upserts have more visibility than the
+// mpb progress bar and log lines alone. A single Metrics holds its own
+// registry; subcommands pull a *Metrics out of app.Deps and record against it
+// from embed.Compute and the upsert and retrieve subcommands alike.
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds every collector embedmeup exposes, registered against its own
+// CollectorRegistry so multiple instances (e.g. in tests) don't collide on
+// the global default registry.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	OpenAIRequestsTotal   *prometheus.CounterVec
+	OpenAIRequestDuration *prometheus.HistogramVec
+	OpenAITokensTotal     *prometheus.CounterVec
+	PineconeUpsertsTotal  *prometheus.CounterVec
+	PineconeQueryDuration prometheus.Histogram
+	ChunksBisectedTotal   prometheus.Counter
+	DedupSkipsTotal       *prometheus.CounterVec
+}
+
+// New creates and registers every embedmeup collector on a fresh registry.
+func New() *Metrics {
+	registry := prometheus.NewRegistry()
+
+	m := &Metrics{
+		Registry: registry,
+		OpenAIRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "embedmeup_openai_requests_total",
+			Help: "Total OpenAI embedding requests, by model and status.",
+		}, []string{"model", "status"}),
+		OpenAIRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "embedmeup_openai_request_duration_seconds",
+			Help:    "Latency of OpenAI embedding requests.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"model"}),
+		OpenAITokensTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "embedmeup_openai_tokens_total",
+			Help: "Total OpenAI tokens consumed, by direction (input or output).",
+		}, []string{"direction"}),
+		PineconeUpsertsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "embedmeup_pinecone_upserts_total",
+			Help: "Total vector store upserts, by status.",
+		}, []string{"status"}),
+		PineconeQueryDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "embedmeup_pinecone_query_duration_seconds",
+			Help:    "Latency of vector store queries.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		ChunksBisectedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "embedmeup_chunks_bisected_total",
+			Help: "Total input records that were split into chunks because they exceeded the token limit.",
+		}),
+		DedupSkipsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "embedmeup_dedup_skips_total",
+			Help: "Total records skipped by the upsert dedup cache, by reason (cached or duplicate).",
+		}, []string{"reason"}),
+	}
+
+	registry.MustRegister(
+		m.OpenAIRequestsTotal,
+		m.OpenAIRequestDuration,
+		m.OpenAITokensTotal,
+		m.PineconeUpsertsTotal,
+		m.PineconeQueryDuration,
+		m.ChunksBisectedTotal,
+		m.DedupSkipsTotal,
+	)
+
+	return m
+}
+
+// BasicAuth, when non-nil, gates Handler behind HTTP basic auth. Both fields
+// must be set via an environment variable at startup, never a flag default,
+// so credentials don't end up in shell history or process listings.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Handler returns the /metrics HTTP handler for m's registry, optionally
+// wrapped in HTTP basic auth for deployments that expose the endpoint
+// publicly.
+func (m *Metrics) Handler(auth *BasicAuth) http.Handler {
+	handler := promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{})
+	if auth == nil {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok ||
+			subtle.ConstantTimeCompare([]byte(user), []byte(auth.Username)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(pass), []byte(auth.Password)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="embedmeup metrics"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// Serve starts an HTTP server exposing m's metrics at /metrics on addr in its
+// own goroutine and returns immediately; listen errors are discarded. Callers
+// shut it down via the returned *http.Server.
+func (m *Metrics) Serve(addr string, auth *BasicAuth) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler(auth))
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		_ = srv.ListenAndServe()
+	}()
+	return srv
+}
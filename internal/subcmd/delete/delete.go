@@ -0,0 +1,43 @@
+// Package delete implements the "delete" subcommand, which clears vectors
+// from the configured vector store namespace.
+package delete
+
+import (
+	"context"
+	"flag"
+
+	"github.com/tbiehn/embedmeup/internal/app"
+	"github.com/tbiehn/embedmeup/internal/vectorstore"
+)
+
+// Command implements subcmd.Command for "delete".
+type Command struct {
+	flags *flag.FlagSet
+	all   bool
+}
+
+// New returns an unregistered delete Command with its flag set initialized.
+func New() *Command {
+	c := &Command{flags: flag.NewFlagSet("delete", flag.ExitOnError)}
+	c.flags.BoolVar(&c.all, "all", true, "Delete all vectors in the index/namespace.")
+	return c
+}
+
+func (c *Command) Name() string { return "delete" }
+
+func (c *Command) FlagSet() *flag.FlagSet { return c.flags }
+
+// Run deletes vectors from the configured vector store.
+func (c *Command) Run(ctx context.Context, deps *app.Deps) error {
+	req := vectorstore.DeleteRequest{
+		Namespace: deps.Namespace,
+		All:       c.all,
+	}
+	deleteCtx, cancel := deps.RequestContext(ctx)
+	defer cancel()
+	if err := deps.VectorStore.Delete(deleteCtx, req); err != nil {
+		return err
+	}
+	deps.Log.Info("Deleted vectors")
+	return nil
+}
@@ -0,0 +1,131 @@
+// Package retrieve implements the "retrieve" subcommand: read a JSON query
+// record from stdin, embed its configured field, query the vector store for
+// the nearest matches, and print each match's original stored content.
+package retrieve
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/tbiehn/embedmeup/internal/app"
+	"github.com/tbiehn/embedmeup/internal/embed"
+	"github.com/tbiehn/embedmeup/internal/vectorstore"
+)
+
+// EmbeddingResponse pairs a query's input record with the matches retrieved
+// for it.
+type EmbeddingResponse struct {
+	Input    any `json:"Input"`
+	Response any `json:"Response"`
+}
+
+// Command implements subcmd.Command for "retrieve".
+type Command struct {
+	flags      *flag.FlagSet
+	embedParam string
+	topK       int
+}
+
+// New returns an unregistered retrieve Command with its flag set initialized.
+func New() *Command {
+	c := &Command{flags: flag.NewFlagSet("retrieve", flag.ExitOnError)}
+	c.flags.StringVar(&c.embedParam, "param", "search", "Name of JSON string object to compute embedding for.")
+	c.flags.IntVar(&c.topK, "topK", 10, "TopK parameter for retrieval")
+	return c
+}
+
+func (c *Command) Name() string { return "retrieve" }
+
+func (c *Command) FlagSet() *flag.FlagSet { return c.flags }
+
+// Run reads query records from stdin and prints their matches.
+func (c *Command) Run(ctx context.Context, deps *app.Deps) error {
+	dec := json.NewDecoder(os.Stdin)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		var data map[string]any
+		if err := dec.Decode(&data); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("error decoding JSON: %w", err)
+		}
+
+		search, found := data[c.embedParam]
+		if !found {
+			return fmt.Errorf("input didn't contain a search parameter")
+		}
+		searchStr, ok := search.(string)
+		if !ok {
+			return fmt.Errorf("parameter %s is not a string", c.embedParam)
+		}
+
+		embedCtx, cancel := deps.RequestContext(ctx)
+		vector, err := embed.Compute(embedCtx, searchStr, deps.RequestsChan, deps.Metrics)
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		jsonData, err := c.query(ctx, deps, vector)
+		if err != nil {
+			return err
+		}
+
+		resp := &EmbeddingResponse{
+			Input:    data,
+			Response: jsonData,
+		}
+
+		jsonStr, err := json.Marshal(resp)
+		if err != nil {
+			return fmt.Errorf("error marshaling JSON: %w", err)
+		}
+		fmt.Println(string(jsonStr))
+	}
+	return nil
+}
+
+func (c *Command) query(ctx context.Context, deps *app.Deps, vector []float32) (any, error) {
+	var filter map[string]any
+	if deps.Namespace != "" {
+		filter = map[string]any{vectorstore.NamespaceFilterKey: deps.Namespace}
+	}
+
+	queryCtx, cancel := deps.RequestContext(ctx)
+	start := time.Now()
+	matches, err := deps.VectorStore.Query(queryCtx, vector, c.topK, filter)
+	deps.Metrics.PineconeQueryDuration.Observe(time.Since(start).Seconds())
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonData []interface{}
+	for _, match := range matches {
+		getCtx, cancel := deps.RequestContext(ctx)
+		data, err := deps.ContentStore.Get(getCtx, match.ID)
+		cancel()
+		if err != nil {
+			deps.Log.Errorf("error reading content for %s: %v", match.ID, err)
+			continue
+		}
+
+		var jsonObject interface{}
+		if err := json.Unmarshal(data, &jsonObject); err != nil {
+			return nil, fmt.Errorf("error unmarshaling JSON: %w", err)
+		}
+		jsonData = append(jsonData, jsonObject)
+	}
+
+	return jsonData, nil
+}
@@ -0,0 +1,40 @@
+// Package stats implements the "stats" subcommand, which reports vector
+// store statistics per namespace.
+package stats
+
+import (
+	"context"
+	"flag"
+
+	"github.com/tbiehn/embedmeup/internal/app"
+)
+
+// Command implements subcmd.Command for "stats".
+type Command struct {
+	flags *flag.FlagSet
+}
+
+// New returns an unregistered stats Command with its flag set initialized.
+func New() *Command {
+	return &Command{flags: flag.NewFlagSet("stats", flag.ExitOnError)}
+}
+
+func (c *Command) Name() string { return "stats" }
+
+func (c *Command) FlagSet() *flag.FlagSet { return c.flags }
+
+// Run prints per-namespace vector counts from the configured vector store.
+func (c *Command) Run(ctx context.Context, deps *app.Deps) error {
+	describeCtx, cancel := deps.RequestContext(ctx)
+	defer cancel()
+	stats, err := deps.VectorStore.Describe(describeCtx)
+	if err != nil {
+		return err
+	}
+
+	deps.Log.Info("Connected to vector store.")
+	for name, ns := range stats.Namespaces {
+		deps.Log.Infof("Index Namespace: %s: %+v", name, ns)
+	}
+	return nil
+}
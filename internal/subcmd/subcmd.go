@@ -0,0 +1,24 @@
+// Package subcmd defines the interface every embedmeup operation implements,
+// so cmd/embedmeup can dispatch to upsert/retrieve/delete/stats without
+// knowing anything about their individual flags or behavior.
+package subcmd
+
+import (
+	"context"
+	"flag"
+
+	"github.com/tbiehn/embedmeup/internal/app"
+)
+
+// Command is one embedmeup operation (upsert, retrieve, delete, stats). Each
+// implementation owns its own flag set, parses it from the arguments that
+// follow the subcommand name, and runs against the shared app.Deps.
+type Command interface {
+	// Name is the subcommand name as typed on the command line.
+	Name() string
+	// FlagSet returns the subcommand's flag set, ready to be parsed against
+	// the arguments following the subcommand name.
+	FlagSet() *flag.FlagSet
+	// Run executes the subcommand using the parsed flags and shared deps.
+	Run(ctx context.Context, deps *app.Deps) error
+}
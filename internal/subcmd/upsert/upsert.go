@@ -0,0 +1,206 @@
+// Package upsert implements the "upsert" subcommand: read JSON records from
+// stdin, embed the configured field (splitting it into token-sized chunks if
+// needed), store the original record via the content store keyed by content
+// hash, and upsert the resulting vectors to the configured vector store.
+//
+// Records are deduplicated by content hash so repeat runs over an unchanged
+// corpus skip both the OpenAI embedding call and the vector store upsert.
+package upsert
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/tbiehn/embedmeup/internal/app"
+	"github.com/tbiehn/embedmeup/internal/embed"
+	"github.com/tbiehn/embedmeup/internal/vectorstore"
+)
+
+// Command implements subcmd.Command for "upsert".
+type Command struct {
+	flags      *flag.FlagSet
+	embedParam string
+	maxTokens  int
+	force      bool
+
+	seenMu    sync.Mutex
+	seen      map[string]struct{}
+	cached    int
+	duplicate int
+}
+
+// New returns an unregistered upsert Command with its flag set initialized.
+func New() *Command {
+	c := &Command{
+		flags: flag.NewFlagSet("upsert", flag.ExitOnError),
+		seen:  make(map[string]struct{}),
+	}
+	c.flags.StringVar(&c.embedParam, "param", "search", "Name of JSON string object to compute embedding for.")
+	c.flags.IntVar(&c.maxTokens, "tokens", 8191, "Recursive bisection split input if it exceeds this many tokens.")
+	c.flags.BoolVar(&c.force, "force", false, "Bypass the dedup cache and re-embed/re-upsert every record.")
+	return c
+}
+
+func (c *Command) Name() string { return "upsert" }
+
+func (c *Command) FlagSet() *flag.FlagSet { return c.flags }
+
+// Run reads JSON records from stdin and upserts their embeddings.
+func (c *Command) Run(ctx context.Context, deps *app.Deps) error {
+	var wg sync.WaitGroup
+
+	dec := json.NewDecoder(os.Stdin)
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		default:
+		}
+
+		var data map[string]any
+		if err := dec.Decode(&data); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("error decoding JSON: %w", err)
+		}
+
+		search, found := data[c.embedParam]
+		if !found {
+			return fmt.Errorf("input didn't contain the embedding parameter %s", c.embedParam)
+		}
+		searchStr, ok := search.(string)
+		if !ok {
+			return fmt.Errorf("parameter %s is not a string", c.embedParam)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.upsertRecord(ctx, deps, data, searchStr); err != nil {
+				deps.Log.Errorf("error upserting record: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if skipped := c.cached + c.duplicate; skipped > 0 {
+		deps.Log.Infof("skipped %d records (%d cached, %d duplicate)", skipped, c.cached, c.duplicate)
+	}
+	return nil
+}
+
+func (c *Command) upsertRecord(ctx context.Context, deps *app.Deps, data map[string]any, searchStr string) error {
+	chunks := []string{searchStr}
+	if embed.Encode(searchStr) > c.maxTokens {
+		chunks = embed.BisectSplitTokens(searchStr, c.maxTokens)
+		deps.Metrics.ChunksBisectedTotal.Inc()
+		deps.Log.Debugln("Split input ", searchStr, "into chunks;")
+		for _, chunk := range chunks {
+			deps.Log.Debugln("Chunk:", chunk)
+		}
+	}
+
+	for _, chunk := range chunks {
+		data[c.embedParam] = chunk
+
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("error marshaling JSON: %w", err)
+		}
+		hash := sha256.Sum256(jsonData)
+		id := fmt.Sprintf("%x", hash)
+
+		if !c.force {
+			if c.markDuplicate(id) {
+				deps.Metrics.DedupSkipsTotal.WithLabelValues("duplicate").Inc()
+				continue
+			}
+			cached, err := c.isCached(ctx, deps, id)
+			if err != nil {
+				return err
+			}
+			if cached {
+				c.seenMu.Lock()
+				c.cached++
+				c.seenMu.Unlock()
+				deps.Metrics.DedupSkipsTotal.WithLabelValues("cached").Inc()
+				continue
+			}
+		}
+
+		embedCtx, cancel := deps.RequestContext(ctx)
+		embedding, err := embed.Compute(embedCtx, chunk, deps.RequestsChan, deps.Metrics)
+		cancel()
+		if err != nil {
+			deps.Log.Errorf("error computing embedding: %v", err)
+			continue
+		}
+
+		putCtx, cancel := deps.RequestContext(ctx)
+		err = deps.ContentStore.Put(putCtx, id, jsonData)
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		record := vectorstore.Record{
+			ID:     id,
+			Vector: embedding,
+		}
+		if deps.Namespace != "" {
+			record.Metadata = map[string]any{vectorstore.NamespaceFilterKey: deps.Namespace}
+		}
+		upsertCtx, cancel := deps.RequestContext(ctx)
+		err = deps.VectorStore.Upsert(upsertCtx, []vectorstore.Record{record})
+		cancel()
+		if err != nil {
+			deps.Metrics.PineconeUpsertsTotal.WithLabelValues("error").Inc()
+			return err
+		}
+		deps.Metrics.PineconeUpsertsTotal.WithLabelValues("ok").Inc()
+	}
+	return nil
+}
+
+// markDuplicate records id as seen for this run and reports whether it was
+// already seen, collapsing duplicate chunks within the current stdin stream.
+func (c *Command) markDuplicate(id string) bool {
+	c.seenMu.Lock()
+	defer c.seenMu.Unlock()
+	if _, ok := c.seen[id]; ok {
+		c.duplicate++
+		return true
+	}
+	c.seen[id] = struct{}{}
+	return false
+}
+
+// isCached reports whether id was embedded and upserted on a previous run:
+// the original content is still on disk and the vector store still has it.
+func (c *Command) isCached(ctx context.Context, deps *app.Deps, id string) (bool, error) {
+	existsCtx, cancel := deps.RequestContext(ctx)
+	exists, err := deps.ContentStore.Exists(existsCtx, id)
+	cancel()
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, nil
+	}
+
+	var filter map[string]any
+	if deps.Namespace != "" {
+		filter = map[string]any{vectorstore.NamespaceFilterKey: deps.Namespace}
+	}
+
+	vectorCtx, cancel := deps.RequestContext(ctx)
+	defer cancel()
+	return deps.VectorStore.Exists(vectorCtx, id, filter)
+}
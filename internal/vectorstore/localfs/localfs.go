@@ -0,0 +1,180 @@
+// Package localfs is an offline vectorstore.Store backed by bbolt. It exists
+// so users without a Pinecone account can still run embedmeup, and so tests
+// can exercise the upsert/retrieve/delete paths without a network. Query does
+// a brute-force cosine similarity scan, which is fine for the small corpora
+// this backend targets but is not meant to scale the way Pinecone does.
+package localfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/tbiehn/embedmeup/internal/vectorstore"
+)
+
+const defaultNamespace = "_default"
+
+type storedRecord struct {
+	Vector   []float32      `json:"Vector"`
+	Metadata map[string]any `json:"Metadata"`
+}
+
+// Store is a bbolt-backed vectorstore.Store. Each namespace is its own
+// bucket; vectors are stored JSON-encoded under their ID.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens (creating if necessary) a bbolt database at path.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0644, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error opening local vector store %s: %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying bbolt database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func bucketName(namespace string) []byte {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	return []byte(namespace)
+}
+
+func (s *Store) Upsert(ctx context.Context, records []vectorstore.Record) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		buckets := make(map[string]*bolt.Bucket)
+		for _, r := range records {
+			namespace, _ := r.Metadata[vectorstore.NamespaceFilterKey].(string)
+			b, ok := buckets[namespace]
+			if !ok {
+				var err error
+				b, err = tx.CreateBucketIfNotExists(bucketName(namespace))
+				if err != nil {
+					return err
+				}
+				buckets[namespace] = b
+			}
+
+			data, err := json.Marshal(storedRecord{Vector: r.Vector, Metadata: r.Metadata})
+			if err != nil {
+				return fmt.Errorf("error marshaling record %s: %w", r.ID, err)
+			}
+			if err := b.Put([]byte(r.ID), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) Query(ctx context.Context, vec []float32, topK int, filter map[string]any) ([]vectorstore.Match, error) {
+	namespace, _ := filter[vectorstore.NamespaceFilterKey].(string)
+
+	var matches []vectorstore.Match
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketName(namespace))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var rec storedRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return fmt.Errorf("error unmarshaling record %s: %w", k, err)
+			}
+			matches = append(matches, vectorstore.Match{
+				ID:       string(k),
+				Score:    cosineSimilarity(vec, rec.Vector),
+				Metadata: rec.Metadata,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > topK {
+		matches = matches[:topK]
+	}
+	return matches, nil
+}
+
+func (s *Store) Delete(ctx context.Context, req vectorstore.DeleteRequest) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		name := bucketName(req.Namespace)
+		if req.All {
+			if err := tx.DeleteBucket(name); err != nil && err != bolt.ErrBucketNotFound {
+				return err
+			}
+			return nil
+		}
+
+		b := tx.Bucket(name)
+		if b == nil {
+			return nil
+		}
+		for _, id := range req.IDs {
+			if err := b.Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) Describe(ctx context.Context) (vectorstore.Stats, error) {
+	stats := vectorstore.Stats{Namespaces: make(map[string]vectorstore.NamespaceStats)}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			stats.Namespaces[string(name)] = vectorstore.NamespaceStats{VectorCount: int64(b.Stats().KeyN)}
+			return nil
+		})
+	})
+	if err != nil {
+		return vectorstore.Stats{}, err
+	}
+	return stats, nil
+}
+
+func (s *Store) Exists(ctx context.Context, id string, filter map[string]any) (bool, error) {
+	namespace, _ := filter[vectorstore.NamespaceFilterKey].(string)
+
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketName(namespace))
+		if b == nil {
+			return nil
+		}
+		found = b.Get([]byte(id)) != nil
+		return nil
+	})
+	return found, err
+}
+
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) != len(b) || len(a) == 0 {
+		return -1
+	}
+	var dot, magA, magB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		magA += float64(a[i]) * float64(a[i])
+		magB += float64(b[i]) * float64(b[i])
+	}
+	if magA == 0 || magB == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(magA) * math.Sqrt(magB)))
+}
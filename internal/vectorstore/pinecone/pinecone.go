@@ -0,0 +1,114 @@
+// Package pinecone adapts go-pinecone's IndexClient to the vectorstore.Store
+// interface. This is the same client and call pattern embedmeup has always
+// used; it is just no longer called directly from the subcommands.
+package pinecone
+
+import (
+	"context"
+	"fmt"
+
+	gopinecone "github.com/nekomeowww/go-pinecone"
+
+	"github.com/tbiehn/embedmeup/internal/vectorstore"
+)
+
+// Store wraps a *gopinecone.IndexClient as a vectorstore.Store.
+type Store struct {
+	client *gopinecone.IndexClient
+}
+
+// New wraps an already-configured Pinecone IndexClient.
+func New(client *gopinecone.IndexClient) *Store {
+	return &Store{client: client}
+}
+
+func (s *Store) Upsert(ctx context.Context, records []vectorstore.Record) error {
+	order := make([]string, 0, 1)
+	byNamespace := make(map[string][]*gopinecone.Vector)
+	for _, r := range records {
+		namespace, _ := r.Metadata[vectorstore.NamespaceFilterKey].(string)
+		if _, ok := byNamespace[namespace]; !ok {
+			order = append(order, namespace)
+		}
+		byNamespace[namespace] = append(byNamespace[namespace], &gopinecone.Vector{
+			ID:     r.ID,
+			Values: r.Vector,
+		})
+	}
+
+	for _, namespace := range order {
+		params := gopinecone.UpsertVectorsParams{Vectors: byNamespace[namespace]}
+		if namespace != "" {
+			params.Namespace = namespace
+		}
+		if _, err := s.client.UpsertVectors(ctx, params); err != nil {
+			return fmt.Errorf("error upserting vectors to Pinecone: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) Query(ctx context.Context, vec []float32, topK int, filter map[string]any) ([]vectorstore.Match, error) {
+	params := gopinecone.QueryParams{
+		Vector: vec,
+		TopK:   int64(topK),
+	}
+	if ns, ok := filter[vectorstore.NamespaceFilterKey].(string); ok && ns != "" {
+		params.Namespace = ns
+	}
+
+	resp, err := s.client.Query(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("error querying vectors from Pinecone: %w", err)
+	}
+
+	matches := make([]vectorstore.Match, 0, len(resp.Matches))
+	for _, m := range resp.Matches {
+		matches = append(matches, vectorstore.Match{
+			ID:    m.ID,
+			Score: float32(m.Score),
+		})
+	}
+	return matches, nil
+}
+
+func (s *Store) Delete(ctx context.Context, req vectorstore.DeleteRequest) error {
+	params := gopinecone.DeleteVectorsParams{
+		DeleteAll: req.All,
+		IDs:       req.IDs,
+	}
+	if req.Namespace != "" {
+		params.Namespace = req.Namespace
+	}
+	if err := s.client.DeleteVectors(ctx, params); err != nil {
+		return fmt.Errorf("error deleting vectors from Pinecone: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) Describe(ctx context.Context) (vectorstore.Stats, error) {
+	resp, err := s.client.DescribeIndexStats(ctx, gopinecone.DescribeIndexStatsParams{})
+	if err != nil {
+		return vectorstore.Stats{}, fmt.Errorf("error describing Pinecone index: %w", err)
+	}
+
+	stats := vectorstore.Stats{Namespaces: make(map[string]vectorstore.NamespaceStats, len(resp.Namespaces))}
+	for name, ns := range resp.Namespaces {
+		stats.Namespaces[name] = vectorstore.NamespaceStats{VectorCount: int64(ns.VectorCount)}
+	}
+	return stats, nil
+}
+
+func (s *Store) Exists(ctx context.Context, id string, filter map[string]any) (bool, error) {
+	params := gopinecone.FetchVectorsParams{IDs: []string{id}}
+	if ns, ok := filter[vectorstore.NamespaceFilterKey].(string); ok && ns != "" {
+		params.Namespace = ns
+	}
+
+	resp, err := s.client.FetchVectors(ctx, params)
+	if err != nil {
+		return false, fmt.Errorf("error fetching vector %s from Pinecone: %w", id, err)
+	}
+	_, found := resp.Vectors[id]
+	return found, nil
+}
@@ -0,0 +1,62 @@
+// Package vectorstore defines the backend-agnostic interface upsert,
+// retrieve, delete, and stats are written against, so embedmeup is not
+// hard-wired to Pinecone. See the pinecone and localfs subpackages for
+// implementations.
+package vectorstore
+
+import "context"
+
+// Record is a single vector to upsert, along with optional metadata. The
+// localfs backend stores Metadata alongside the vector; the pinecone backend
+// reads only its NamespaceFilterKey entry to pick the namespace and does not
+// send the rest.
+type Record struct {
+	ID       string
+	Vector   []float32
+	Metadata map[string]any
+}
+
+// Match is a single result from a Query, ranked by Score (higher is closer).
+type Match struct {
+	ID       string
+	Score    float32
+	Metadata map[string]any
+}
+
+// DeleteRequest describes what to remove from a Store. If All is true, IDs
+// is ignored and every vector in Namespace is deleted.
+type DeleteRequest struct {
+	Namespace string
+	IDs       []string
+	All       bool
+}
+
+// NamespaceStats reports the vector count for a single namespace.
+type NamespaceStats struct {
+	VectorCount int64
+}
+
+// Stats reports index-wide statistics, keyed by namespace.
+type Stats struct {
+	Namespaces map[string]NamespaceStats
+}
+
+// NamespaceFilterKey is the well-known filter key Query accepts to scope a
+// search to a single namespace, since namespace is the only filter dimension
+// Pinecone and localfs currently share.
+const NamespaceFilterKey = "namespace"
+
+// Store is the backend-agnostic interface embedmeup upserts, queries,
+// deletes, and describes against. The pinecone subpackage wraps the hosted
+// Pinecone service; the localfs subpackage offers an offline, bbolt-backed
+// alternative for small corpora and tests.
+type Store interface {
+	Upsert(ctx context.Context, records []Record) error
+	Query(ctx context.Context, vec []float32, topK int, filter map[string]any) ([]Match, error)
+	Delete(ctx context.Context, req DeleteRequest) error
+	Describe(ctx context.Context) (Stats, error)
+	// Exists reports whether a vector with the given ID is already present.
+	// The upsert dedup cache uses it to skip recomputing embeddings for
+	// inputs that have not changed since a previous run.
+	Exists(ctx context.Context, id string, filter map[string]any) (bool, error)
+}